@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moby/moby/pkg/stdcopy"
+	"golang.org/x/term"
+)
+
+// ttyRestoreMu protege ttyRestore, que handleInterrupt usa para devolver la
+// terminal local a su modo normal antes de un os.Exit: ese os.Exit se salta
+// los defer de runAttached, así que sin este gancho una señal recibida
+// mientras la terminal está en modo raw la deja rota al morir el proceso.
+var (
+	ttyRestoreMu sync.Mutex
+	ttyRestore   func()
+)
+
+// restoreTTYOnSignal restaura la terminal local si runAttached la tiene en
+// modo raw en este momento. No hace nada si no hay ninguna sesión con TTY activa.
+func restoreTTYOnSignal() {
+	ttyRestoreMu.Lock()
+	restore := ttyRestore
+	ttyRestoreMu.Unlock()
+	if restore != nil {
+		restore()
+	}
+}
+
+// resizeFunc envía el tamaño actual de la terminal (filas, columnas) al daemon.
+type resizeFunc func(ctx context.Context, height, width uint) error
+
+// attachSession agrupa la conexión hijacked, si debe reenviarse stdin, y el
+// callback de resize que usan tanto `start -i` como `exec` para compartir la
+// misma lógica de attach.
+type attachSession struct {
+	conn   types.HijackedResponse
+	stdin  bool
+	tty    bool
+	resize resizeFunc
+}
+
+// runAttached copia stdin/stdout/stderr entre la terminal local y el contenedor.
+// Con tty=true pone la terminal local en modo raw y propaga SIGWINCH; sin tty
+// demultiplexa stdout/stderr con stdcopy.
+func runAttached(ctx context.Context, sess attachSession) error {
+	if sess.tty && term.IsTerminal(int(os.Stdin.Fd())) {
+		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err == nil {
+			ttyRestoreMu.Lock()
+			ttyRestore = func() { term.Restore(int(os.Stdin.Fd()), oldState) }
+			ttyRestoreMu.Unlock()
+			defer func() {
+				ttyRestoreMu.Lock()
+				ttyRestore = nil
+				ttyRestoreMu.Unlock()
+				term.Restore(int(os.Stdin.Fd()), oldState)
+			}()
+		}
+
+		if width, height, err := term.GetSize(int(os.Stdin.Fd())); err == nil {
+			_ = sess.resize(ctx, uint(height), uint(width))
+		}
+
+		stop := watchTTYResize(ctx, func(height, width uint) {
+			_ = sess.resize(ctx, height, width)
+		})
+		defer stop()
+	}
+
+	// El copiado de stdin sólo se inicia cuando realmente hay que adjuntarlo.
+	// Su lectura de os.Stdin nunca ve EOF desde una terminal, así que no se
+	// espera a que termine: cuando el lado remoto cierra la conexión el
+	// proceso retorna y esta goroutine se descarta con él.
+	if sess.stdin {
+		go io.Copy(sess.conn.Conn, os.Stdin)
+	}
+
+	var copyErr error
+	if sess.tty {
+		_, copyErr = io.Copy(os.Stdout, sess.conn.Reader)
+	} else {
+		_, copyErr = stdcopy.StdCopy(os.Stdout, os.Stderr, sess.conn.Reader)
+	}
+
+	sess.conn.Close()
+
+	if copyErr == io.EOF {
+		return nil
+	}
+	return copyErr
+}
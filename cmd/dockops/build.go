@@ -0,0 +1,294 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moby/moby/pkg/jsonmessage"
+)
+
+// buildArgs acumula los --build-arg key=value repetidos en la línea de comandos.
+type buildArgs map[string]*string
+
+func (b buildArgs) String() string {
+	pairs := make([]string, 0, len(b))
+	for k, v := range b {
+		if v == nil {
+			pairs = append(pairs, k)
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, *v))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (b buildArgs) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("formato de build-arg inválido, use key=value: %s", value)
+	}
+	v := parts[1]
+	b[parts[0]] = &v
+	return nil
+}
+
+func (b buildArgs) Type() string {
+	return "stringArray"
+}
+
+func buildImage(contextPath, dockerfile, tag string, args buildArgs) error {
+	ctx := context.Background()
+
+	opts := types.ImageBuildOptions{
+		Dockerfile: dockerfile,
+		Tags:       []string{tag},
+		BuildArgs:  args,
+		Remove:     true,
+	}
+
+	if isRemoteContext(contextPath) {
+		opts.RemoteContext = contextPath
+		return runBuild(ctx, nil, opts)
+	}
+
+	var buildContext io.Reader
+	if contextPath == "-" {
+		buildContext = os.Stdin
+	} else {
+		tarball, err := tarBuildContext(contextPath)
+		if err != nil {
+			return fmt.Errorf("error al preparar el contexto de build: %w", err)
+		}
+		defer tarball.Close()
+		buildContext = tarball
+	}
+
+	return runBuild(ctx, buildContext, opts)
+}
+
+func isRemoteContext(contextPath string) bool {
+	return strings.HasPrefix(contextPath, "http://") ||
+		strings.HasPrefix(contextPath, "https://") ||
+		strings.HasPrefix(contextPath, "git://") ||
+		strings.HasSuffix(contextPath, ".git")
+}
+
+func runBuild(ctx context.Context, buildContext io.Reader, opts types.ImageBuildOptions) error {
+	resp, err := dockerClient.ImageBuild(ctx, buildContext, opts)
+	if err != nil {
+		return fmt.Errorf("error al iniciar el build: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var imageID string
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("error al decodificar el progreso del build: %w", err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("error en el build: %s", msg.Error.Message)
+		}
+
+		if msg.Aux != nil {
+			var aux struct {
+				ID string `json:"ID"`
+			}
+			if err := json.Unmarshal(*msg.Aux, &aux); err == nil && aux.ID != "" {
+				imageID = aux.ID
+			}
+			continue
+		}
+
+		if msg.Progress != nil {
+			fmt.Printf("\r%s: %s", msg.Status, msg.Progress.String())
+		} else if msg.Stream != "" {
+			fmt.Print(msg.Stream)
+		} else if msg.Status != "" {
+			fmt.Println(msg.Status)
+		}
+	}
+
+	if imageID != "" {
+		fmt.Printf("\nImagen construida exitosamente: %s\n", imageID)
+	} else {
+		fmt.Println("\nImagen construida exitosamente")
+	}
+	return nil
+}
+
+// tarBuildContext empaqueta el directorio del contexto en un tar.gz, honrando .dockerignore.
+func tarBuildContext(root string) (io.ReadCloser, error) {
+	ignorePatterns, err := readDockerignore(root)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	gw := gzip.NewWriter(pw)
+	tw := tar.NewWriter(gw)
+
+	go func() {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == root {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			relPath = filepath.ToSlash(relPath)
+
+			if matchesIgnore(relPath, ignorePatterns) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			var linkTarget string
+			if info.Mode()&os.ModeSymlink != 0 {
+				linkTarget, err = os.Readlink(path)
+				if err != nil {
+					return err
+				}
+			}
+
+			header, err := tar.FileInfoHeader(info, linkTarget)
+			if err != nil {
+				return err
+			}
+			header.Name = relPath
+
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+
+			if !info.IsDir() && info.Mode()&os.ModeSymlink == 0 {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				if _, err := io.Copy(tw, f); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		closeErr := err
+		if cerr := tw.Close(); closeErr == nil {
+			closeErr = cerr
+		}
+		if cerr := gw.Close(); closeErr == nil {
+			closeErr = cerr
+		}
+		pw.CloseWithError(closeErr)
+	}()
+
+	return pr, nil
+}
+
+// readDockerignore lee los patrones de exclusión definidos en .dockerignore, si existe.
+func readDockerignore(root string) ([]string, error) {
+	f, err := os.Open(filepath.Join(root, ".dockerignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error al leer .dockerignore: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer .dockerignore: %w", err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// matchesIgnore comprueba si relPath coincide con alguno de los patrones de .dockerignore.
+func matchesIgnore(relPath string, patterns []string) bool {
+	pathSegs := strings.Split(relPath, "/")
+
+	ignored := false
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		if negate {
+			pattern = strings.TrimPrefix(pattern, "!")
+		}
+
+		if matchSegments(patternSegments(pattern), pathSegs) {
+			ignored = !negate
+		}
+	}
+	return ignored
+}
+
+// patternSegments separa un patrón de .dockerignore en segmentos de ruta. Un
+// patrón que empieza por "/" queda anclado a la raíz del contexto. Uno de un
+// solo segmento sin "/" inicial (p.ej. "node_modules" o "*.log", con o sin
+// "/" final) no está anclado, así que se le antepone "**" para que coincida a
+// cualquier profundidad; el resto se compara desde la raíz.
+func patternSegments(pattern string) []string {
+	anchored := strings.HasPrefix(pattern, "/")
+	segs := strings.Split(strings.Trim(pattern, "/"), "/")
+	if !anchored && len(segs) == 1 {
+		return append([]string{"**"}, segs...)
+	}
+	return segs
+}
+
+// matchSegments compara los segmentos de un patrón contra los de una ruta,
+// segmento a segmento, con soporte para "**" (cero o más directorios), tal
+// como lo hace .dockerignore.
+func matchSegments(patternSegs, pathSegs []string) bool {
+	if len(patternSegs) == 0 {
+		return len(pathSegs) == 0
+	}
+
+	if patternSegs[0] == "**" {
+		if matchSegments(patternSegs[1:], pathSegs) {
+			return true
+		}
+		if len(pathSegs) == 0 {
+			return false
+		}
+		return matchSegments(patternSegs, pathSegs[1:])
+	}
+
+	if len(pathSegs) == 0 {
+		return false
+	}
+	if matched, _ := filepath.Match(patternSegs[0], pathSegs[0]); !matched {
+		return false
+	}
+	return matchSegments(patternSegs[1:], pathSegs[1:])
+}
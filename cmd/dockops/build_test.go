@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestMatchesIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		relPath  string
+		patterns []string
+		want     bool
+	}{
+		{"sin patrones", "main.go", nil, false},
+		{"match exacto en la raíz", "node_modules", []string{"node_modules"}, true},
+		{"patrón sin / excluye anidado", "src/node_modules", []string{"node_modules"}, true},
+		{"patrón sin / excluye anidado a varios niveles", "src/lib/node_modules", []string{"node_modules"}, true},
+		{"glob sin / excluye anidado", "pkg/debug.log", []string{"*.log"}, true},
+		{"glob no coincide", "pkg/debug.txt", []string{"*.log"}, false},
+		{"patrón anclado con / sólo coincide en la raíz", "src/build", []string{"/build"}, false},
+		{"patrón anclado con / coincide en la raíz", "build", []string{"/build"}, true},
+		{"negación restaura un archivo excluido", "node_modules/keep.txt", []string{"node_modules", "!node_modules/keep.txt"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesIgnore(tt.relPath, tt.patterns); got != tt.want {
+				t.Errorf("matchesIgnore(%q, %v) = %v, want %v", tt.relPath, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	dockopsEvents "github.com/elliotsecops/DockOps/internal/events"
+)
+
+// eventFilters acumula las repeticiones de --filter type=container,event=die,...
+type eventFilters []string
+
+func (f *eventFilters) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *eventFilters) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func (f *eventFilters) Type() string {
+	return "stringArray"
+}
+
+func (f eventFilters) toFilterArgs() (filters.Args, error) {
+	args := filters.NewArgs()
+	for _, raw := range f {
+		for _, pair := range strings.Split(raw, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return args, fmt.Errorf("formato de filtro inválido, use key=value: %s", pair)
+			}
+			args.Add(kv[0], kv[1])
+		}
+	}
+	return args, nil
+}
+
+func runEvents(since, until string, rawFilters eventFilters, format string) error {
+	sinceTS, err := normalizeTimestamp(since)
+	if err != nil {
+		return fmt.Errorf("--since inválido: %w", err)
+	}
+	untilTS, err := normalizeTimestamp(until)
+	if err != nil {
+		return fmt.Errorf("--until inválido: %w", err)
+	}
+
+	filterArgs, err := rawFilters.toFilterArgs()
+	if err != nil {
+		return err
+	}
+
+	var tmpl *template.Template
+	if format != "" {
+		tmpl, err = template.New("events").Parse(format)
+		if err != nil {
+			return fmt.Errorf("error al parsear el formato: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := dockopsEvents.NewEventBus(dockerClient)
+	msgCh := bus.Subscribe(ctx, events.ListOptions{
+		Since:   sinceTS,
+		Until:   untilTS,
+		Filters: filterArgs,
+	})
+
+	for msg := range msgCh {
+		if tmpl != nil {
+			if err := tmpl.Execute(os.Stdout, msg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error al aplicar el formato: %v\n", err)
+				continue
+			}
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("%s %s %s %s (id=%s)\n",
+			time.Unix(0, msg.TimeNano).Format(time.RFC3339),
+			msg.Type, msg.Action, msg.Actor.Attributes["name"], msg.Actor.ID)
+	}
+
+	return nil
+}
+
+// normalizeTimestamp acepta tanto marcas de tiempo RFC3339 como duraciones
+// relativas (p.ej. "10m") y las devuelve normalizadas para la API de eventos.
+func normalizeTimestamp(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return value, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d).Format(time.RFC3339Nano), nil
+	}
+	return "", fmt.Errorf("use RFC3339 o una duración relativa como \"10m\": %s", value)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// execEnv acumula las variables -e KEY=VAL repetidas en la línea de comandos.
+type execEnv []string
+
+func (e *execEnv) String() string {
+	return fmt.Sprintf("%v", []string(*e))
+}
+
+func (e *execEnv) Set(value string) error {
+	*e = append(*e, value)
+	return nil
+}
+
+func (e *execEnv) Type() string {
+	return "stringArray"
+}
+
+// runExec ejecuta un comando dentro de un contenedor ya en marcha, adjuntando
+// la terminal local cuando interactive/tty lo requieren, y devuelve el código
+// de salida real del comando ejecutado.
+func runExec(containerID string, cmdArgs []string, interactive, tty bool, env []string, user, workdir string) (int, error) {
+	ctx := context.Background()
+
+	execConfig := container.ExecOptions{
+		AttachStdin:  interactive,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+		Env:          env,
+		User:         user,
+		WorkingDir:   workdir,
+		Cmd:          cmdArgs,
+	}
+
+	created, err := dockerClient.ContainerExecCreate(ctx, containerID, execConfig)
+	if err != nil {
+		return 1, fmt.Errorf("error al crear el exec: %w", err)
+	}
+
+	resp, err := dockerClient.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: tty})
+	if err != nil {
+		return 1, fmt.Errorf("error al adjuntar el exec: %w", err)
+	}
+	defer resp.Close()
+
+	sess := attachSession{
+		conn:  resp,
+		stdin: interactive,
+		tty:   tty,
+		resize: func(ctx context.Context, height, width uint) error {
+			return dockerClient.ContainerExecResize(ctx, created.ID, container.ResizeOptions{Height: height, Width: width})
+		},
+	}
+
+	if err := runAttached(ctx, sess); err != nil {
+		return 1, fmt.Errorf("error durante el exec: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 1, fmt.Errorf("error al inspeccionar el exec: %w", err)
+	}
+
+	return inspect.ExitCode, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/elliotsecops/DockOps/internal/imageref"
+	"github.com/elliotsecops/DockOps/internal/registry"
+)
+
+// inspectManifest imprime, por plataforma, los digests de la manifest list
+// de imageName usando el cliente registry v2.
+func inspectManifest(imageName string) error {
+	img, err := imageref.Parse(imageName)
+	if err != nil {
+		return fmt.Errorf("nombre de imagen inválido: %w", err)
+	}
+
+	regClient := registry.NewClient(&http.Client{})
+	manifests, err := regClient.Manifests(context.Background(), img.Registry, img.Repo, img.Tag)
+	if err != nil {
+		return fmt.Errorf("error al obtener el manifest: %w", err)
+	}
+
+	fmt.Printf("%-14s %-10s %-10s %10s   %s\n", "ARCHITECTURE", "OS", "VARIANT", "SIZE", "DIGEST")
+	for _, m := range manifests {
+		variant := m.Variant
+		if variant == "" {
+			variant = "-"
+		}
+		fmt.Printf("%-14s %-10s %-10s %10d   %s\n", m.Architecture, m.OS, variant, m.Size, m.Digest)
+	}
+	return nil
+}
@@ -2,9 +2,7 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"math/rand"
 	"os"
 	"os/signal"
@@ -16,7 +14,6 @@ import (
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/go-connections/nat"
 	"github.com/moby/moby/client"
-	"github.com/moby/moby/pkg/jsonmessage"
 	"github.com/moby/moby/pkg/stdcopy"
 	"github.com/spf13/cobra"
 )
@@ -24,9 +21,31 @@ import (
 var (
 	dockerClient *client.Client
 	// Flags para el comando start
-	containerPort string
-	volumePath    string
-	command       string
+	containerPort    string
+	volumePath       string
+	command          string
+	startInteractive bool
+	startPlatform    string
+	// Flags para el comando pull
+	pullPlatform string
+	// Flags para el comando build
+	buildFile string
+	buildTag  string
+	buildArgsFlag = buildArgs{}
+	// Flags para el comando stats
+	statsNoStream bool
+	statsFormat   string
+	// Flags para el comando exec
+	execInteractive bool
+	execTty         bool
+	execUser        string
+	execWorkdir     string
+	execEnvFlag     = execEnv{}
+	// Flags para el comando events
+	eventsSince       string
+	eventsUntil       string
+	eventsFormat      string
+	eventsFilterFlags = eventFilters{}
 )
 
 func init() {
@@ -53,7 +72,7 @@ func main() {
 		Short: "Inicia un contenedor de una imagen",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := startImage(args[0]); err != nil {
+			if err := startImage(args[0], startInteractive, startPlatform); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
@@ -64,6 +83,113 @@ func main() {
 	startCmd.Flags().StringVarP(&containerPort, "port", "p", "", "Puerto para exponer (formato: host:container)")
 	startCmd.Flags().StringVarP(&volumePath, "volume", "v", "", "Volumen para montar (formato: host:container)")
 	startCmd.Flags().StringVarP(&command, "cmd", "c", "/bin/sh", "Comando para ejecutar")
+	startCmd.Flags().BoolVarP(&startInteractive, "interactive", "i", false, "Adjuntar la terminal local al contenedor")
+	startCmd.Flags().StringVar(&startPlatform, "platform", "", "Plataforma a usar, p.ej. linux/arm64 (formato: os/arch[/variant])")
+
+	var pullCmd = &cobra.Command{
+		Use:   "pull [imagen]",
+		Short: "Descarga una imagen sin crear un contenedor",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := pullImage(args[0], pullPlatform); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	pullCmd.Flags().StringVar(&pullPlatform, "platform", "", "Plataforma a descargar, p.ej. linux/arm64 (formato: os/arch[/variant])")
+
+	var inspectCmd = &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspecciona recursos de Docker",
+	}
+
+	var inspectManifestCmd = &cobra.Command{
+		Use:   "manifest [imagen]",
+		Short: "Muestra los digests por plataforma de una manifest list",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := inspectManifest(args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	inspectCmd.AddCommand(inspectManifestCmd)
+
+	var buildCmd = &cobra.Command{
+		Use:   "build [PATH | URL | -]",
+		Short: "Construye una imagen a partir de un Dockerfile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := buildImage(args[0], buildFile, buildTag, buildArgsFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	buildCmd.Flags().StringVarP(&buildFile, "file", "f", "Dockerfile", "Nombre del Dockerfile a usar")
+	buildCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Nombre y tag de la imagen (formato: name:tag)")
+	buildCmd.Flags().VarP(buildArgsFlag, "build-arg", "", "Build-time variable (formato: key=value, repetible)")
+
+	var statsCmd = &cobra.Command{
+		Use:   "stats [containerId...]",
+		Short: "Muestra métricas de recursos en vivo de uno o más contenedores",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := showStats(args, statsNoStream, statsFormat); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	statsCmd.Flags().BoolVar(&statsNoStream, "no-stream", false, "Mostrar una única instantánea y salir")
+	statsCmd.Flags().StringVar(&statsFormat, "format", "", "Formatear la salida usando un template de Go")
+
+	var execCmd = &cobra.Command{
+		Use:   "exec [-it] [containerId] [cmd...]",
+		Short: "Ejecuta un comando dentro de un contenedor en marcha",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			containerID := args[0]
+			cmdArgs := args[1:]
+			if len(cmdArgs) == 0 {
+				cmdArgs = []string{"/bin/sh"}
+			}
+			exitCode, err := runExec(containerID, cmdArgs, execInteractive, execTty, []string(execEnvFlag), execUser, execWorkdir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(exitCode)
+		},
+	}
+
+	execCmd.Flags().BoolVarP(&execInteractive, "interactive", "i", false, "Mantener STDIN abierto")
+	execCmd.Flags().BoolVarP(&execTty, "tty", "t", false, "Asignar una pseudo-TTY")
+	execCmd.Flags().VarP(&execEnvFlag, "env", "e", "Variable de entorno KEY=VAL (repetible)")
+	execCmd.Flags().StringVarP(&execUser, "user", "u", "", "Usuario con el que ejecutar el comando")
+	execCmd.Flags().StringVarP(&execWorkdir, "workdir", "w", "", "Directorio de trabajo dentro del contenedor")
+
+	var eventsCmd = &cobra.Command{
+		Use:   "events",
+		Short: "Transmite eventos del daemon de Docker",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runEvents(eventsSince, eventsUntil, eventsFilterFlags, eventsFormat); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Mostrar eventos desde este timestamp (RFC3339 o duración relativa, p.ej. 10m)")
+	eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "Detener la transmisión en este timestamp (RFC3339 o duración relativa)")
+	eventsCmd.Flags().VarP(&eventsFilterFlags, "filter", "f", "Filtrar eventos, p.ej. type=container,event=die (repetible)")
+	eventsCmd.Flags().StringVar(&eventsFormat, "format", "", "Formatear la salida usando un template de Go")
 
 	var stopCmd = &cobra.Command{
 		Use:   "stop [containerId]",
@@ -112,7 +238,7 @@ func main() {
 		},
 	}
 
-	rootCmd.AddCommand(startCmd, stopCmd, logsCmd, removeCmd, listCmd)
+	rootCmd.AddCommand(startCmd, pullCmd, inspectCmd, buildCmd, statsCmd, execCmd, eventsCmd, stopCmd, logsCmd, removeCmd, listCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -120,36 +246,16 @@ func main() {
 	}
 }
 
-func startImage(imageName string) error {
+func startImage(imageName string, interactive bool, platform string) error {
 	ctx := context.Background()
 
-	fmt.Printf("Descargando imagen %s...\n", imageName)
-	reader, err := dockerClient.ImagePull(ctx, imageName, image.PullOptions{})
-	if err != nil {
-		return fmt.Errorf("error al descargar la imagen: %v", err)
+	if err := pullImage(imageName, platform); err != nil {
+		return err
 	}
-	defer reader.Close()
 
-	// Mostrar progreso de descarga
-	dec := json.NewDecoder(reader)
-	for {
-		var event jsonmessage.JSONMessage
-		if err := dec.Decode(&event); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("error al decodificar el progreso: %v", err)
-		}
-
-		if event.Error != nil {
-			return fmt.Errorf("error en la descarga: %s", event.Error.Message)
-		}
-
-		if event.Progress != nil {
-			fmt.Printf("\r%s: %s", event.Status, event.Progress.String())
-		} else {
-			fmt.Println(event.Status)
-		}
+	platformSpec, err := parsePlatform(platform)
+	if err != nil {
+		return err
 	}
 
 	// Configuración del contenedor
@@ -183,14 +289,46 @@ func startImage(imageName string) error {
 		hostConfig.Binds = []string{volumePath}
 	}
 
+	if interactive {
+		config.AttachStdin = true
+		config.AttachStdout = true
+		config.AttachStderr = true
+		config.OpenStdin = true
+	}
+
 	fmt.Printf("\nCreando contenedor con imagen %s...\n", imageName)
-	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, containerName)
+	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, platformSpec, containerName)
 	if err != nil {
 		return fmt.Errorf("error al crear el contenedor: %v", err)
 	}
 
 	handleInterrupt(resp.ID)
 
+	if interactive {
+		attachResp, err := dockerClient.ContainerAttach(ctx, resp.ID, container.AttachOptions{
+			Stream: true, Stdin: true, Stdout: true, Stderr: true,
+		})
+		if err != nil {
+			return fmt.Errorf("error al adjuntar el contenedor: %v", err)
+		}
+		defer attachResp.Close()
+
+		fmt.Printf("Iniciando contenedor %s...\n", resp.ID[:12])
+		if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+			return fmt.Errorf("error al iniciar el contenedor: %v", err)
+		}
+
+		sess := attachSession{
+			conn:  attachResp,
+			stdin: true,
+			tty:   true,
+			resize: func(ctx context.Context, height, width uint) error {
+				return dockerClient.ContainerResize(ctx, resp.ID, container.ResizeOptions{Height: height, Width: width})
+			},
+		}
+		return runAttached(ctx, sess)
+	}
+
 	fmt.Printf("Iniciando contenedor %s...\n", resp.ID[:12])
 	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("error al iniciar el contenedor: %v", err)
@@ -287,6 +425,10 @@ func handleInterrupt(containerID string) {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
+		// Restaurar la terminal local antes de salir: os.Exit se salta los
+		// defer de runAttached, así que si la señal llega con la terminal en
+		// modo raw (p.ej. durante `start -i`) hay que devolverla a mano.
+		restoreTTYOnSignal()
 		fmt.Println("\nInterrumpido. Deteniendo el contenedor...")
 		ctx := context.Background()
 		if err := dockerClient.ContainerStop(ctx, containerID, container.StopOptions{}); err != nil {
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/moby/moby/pkg/jsonmessage"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// pullImage descarga imageName, opcionalmente fijando la plataforma, mostrando
+// el progreso de descarga igual que hacía antes `start`.
+func pullImage(imageName, platform string) error {
+	ctx := context.Background()
+
+	fmt.Printf("Descargando imagen %s...\n", imageName)
+	reader, err := dockerClient.ImagePull(ctx, imageName, image.PullOptions{Platform: platform})
+	if err != nil {
+		return fmt.Errorf("error al descargar la imagen: %v", err)
+	}
+	defer reader.Close()
+
+	dec := json.NewDecoder(reader)
+	for {
+		var event jsonmessage.JSONMessage
+		if err := dec.Decode(&event); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("error al decodificar el progreso: %v", err)
+		}
+
+		if event.Error != nil {
+			return fmt.Errorf("error en la descarga: %s", event.Error.Message)
+		}
+
+		if event.Progress != nil {
+			fmt.Printf("\r%s: %s", event.Status, event.Progress.String())
+		} else {
+			fmt.Println(event.Status)
+		}
+	}
+
+	fmt.Printf("\nImagen %s descargada exitosamente\n", imageName)
+	return nil
+}
+
+// parsePlatform traduce un flag --platform (formato "os/arch" u
+// "os/arch/variant") al tipo que espera ContainerCreate. Una cadena vacía
+// deja que el daemon decida la plataforma.
+func parsePlatform(platform string) (*ocispec.Platform, error) {
+	if platform == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("formato de plataforma inválido, use os/arch[/variant]: %s", platform)
+	}
+
+	spec := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		spec.Variant = parts[2]
+	}
+	return spec, nil
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// watchTTYResize escucha SIGWINCH y notifica el nuevo tamaño de la terminal.
+// Devuelve una función para detener la escucha.
+func watchTTYResize(ctx context.Context, onResize func(height, width uint)) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				if width, height, err := term.GetSize(int(os.Stdin.Fd())); err == nil {
+					onResize(uint(height), uint(width))
+				}
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
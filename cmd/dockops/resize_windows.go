@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import "context"
+
+// watchTTYResize es un no-op en Windows: no existe SIGWINCH.
+func watchTTYResize(ctx context.Context, onResize func(height, width uint)) func() {
+	return func() {}
+}
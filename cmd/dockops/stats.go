@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// statsRow contiene los valores ya calculados para una fila de la tabla de stats.
+type statsRow struct {
+	ID         string
+	Name       string
+	CPUPercent float64
+	MemUsage   uint64
+	MemLimit   uint64
+	MemPercent float64
+	NetRX      uint64
+	NetTX      uint64
+	BlockRead  uint64
+	BlockWrite uint64
+}
+
+// statsRenderer redibuja la tabla de stats en su sitio usando movimientos de cursor ANSI.
+type statsRenderer struct {
+	mu      sync.Mutex
+	rows    map[string]statsRow
+	order   []string
+	printed bool
+}
+
+func newStatsRenderer(ids []string) *statsRenderer {
+	return &statsRenderer{
+		rows:  make(map[string]statsRow),
+		order: ids,
+	}
+}
+
+func (r *statsRenderer) update(row statsRow) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[row.ID] = row
+	r.draw()
+}
+
+func (r *statsRenderer) draw() {
+	if r.printed {
+		// Mover el cursor al inicio de la tabla y borrar hasta el final de la pantalla.
+		fmt.Printf("\033[%dA\033[J", len(r.order)+1)
+	}
+	r.printed = true
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O")
+	for _, id := range r.order {
+		row, ok := r.rows[id]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\n", shortID(id))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%.2f%%\t%s / %s\t%.2f%%\t%s / %s\t%s / %s\n",
+			shortID(row.ID), row.CPUPercent,
+			humanBytes(row.MemUsage), humanBytes(row.MemLimit), row.MemPercent,
+			humanBytes(row.NetRX), humanBytes(row.NetTX),
+			humanBytes(row.BlockRead), humanBytes(row.BlockWrite))
+	}
+	tw.Flush()
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func humanBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for d := n / unit; d >= unit; d /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func showStats(containerIDs []string, noStream bool, format string) error {
+	ctx := context.Background()
+
+	if format != "" {
+		return showStatsFormatted(ctx, containerIDs, noStream, format)
+	}
+
+	renderer := newStatsRenderer(containerIDs)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(containerIDs))
+	for _, id := range containerIDs {
+		wg.Add(1)
+		go func(containerID string) {
+			defer wg.Done()
+			if err := streamContainerStats(ctx, containerID, noStream, func(row statsRow) {
+				renderer.update(row)
+			}); err != nil {
+				errCh <- fmt.Errorf("error al obtener stats de %s: %w", containerID, err)
+			}
+		}(id)
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		return err
+	}
+	return nil
+}
+
+func showStatsFormatted(ctx context.Context, containerIDs []string, noStream bool, format string) error {
+	tmpl, err := template.New("stats").Parse(format)
+	if err != nil {
+		return fmt.Errorf("error al parsear el formato: %w", err)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(containerIDs))
+	for _, id := range containerIDs {
+		wg.Add(1)
+		go func(containerID string) {
+			defer wg.Done()
+			if err := streamContainerStats(ctx, containerID, noStream, func(row statsRow) {
+				mu.Lock()
+				defer mu.Unlock()
+				if err := tmpl.Execute(os.Stdout, row); err != nil {
+					fmt.Fprintf(os.Stderr, "Error al aplicar el formato: %v\n", err)
+					return
+				}
+				fmt.Println()
+			}); err != nil {
+				errCh <- fmt.Errorf("error al obtener stats de %s: %w", containerID, err)
+			}
+		}(id)
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		return err
+	}
+	return nil
+}
+
+func streamContainerStats(ctx context.Context, containerID string, noStream bool, onUpdate func(statsRow)) error {
+	resp, err := dockerClient.ContainerStats(ctx, containerID, !noStream)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var raw container.StatsResponse
+		if err := dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		onUpdate(toStatsRow(containerID, raw))
+		if noStream {
+			return nil
+		}
+	}
+}
+
+func toStatsRow(containerID string, raw container.StatsResponse) statsRow {
+	delta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+
+	var cpuPercent float64
+	if systemDelta > 0 && delta > 0 {
+		cpuPercent = (delta / systemDelta) * float64(len(raw.CPUStats.CPUUsage.PercpuUsage)) * 100.0
+	}
+
+	memUsage := raw.MemoryStats.Usage
+	if cache, ok := raw.MemoryStats.Stats["cache"]; ok {
+		memUsage -= cache
+	}
+
+	var memPercent float64
+	if raw.MemoryStats.Limit > 0 {
+		memPercent = (float64(memUsage) / float64(raw.MemoryStats.Limit)) * 100.0
+	}
+
+	var netRX, netTX uint64
+	for _, net := range raw.Networks {
+		netRX += net.RxBytes
+		netTX += net.TxBytes
+	}
+
+	var blockRead, blockWrite uint64
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch entry.Op {
+		case "read", "Read":
+			blockRead += entry.Value
+		case "write", "Write":
+			blockWrite += entry.Value
+		}
+	}
+
+	return statsRow{
+		ID:         containerID,
+		Name:       raw.Name,
+		CPUPercent: cpuPercent,
+		MemUsage:   memUsage,
+		MemLimit:   raw.MemoryStats.Limit,
+		MemPercent: memPercent,
+		NetRX:      netRX,
+		NetTX:      netTX,
+		BlockRead:  blockRead,
+		BlockWrite: blockWrite,
+	}
+}
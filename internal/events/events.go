@@ -0,0 +1,94 @@
+// Package events expone un EventBus reutilizable sobre el stream de eventos
+// del daemon de Docker, con reconexión automática ante desconexiones
+// transitorias.
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+const (
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// EventBus distribuye los eventos del daemon a quien los suscriba, de modo
+// que otros comandos (p.ej. `start` esperando un `die`/`health_status`) no
+// necesiten implementar su propio polling.
+type EventBus struct {
+	client *client.Client
+}
+
+// NewEventBus crea un EventBus respaldado por el cliente Docker dado.
+func NewEventBus(c *client.Client) *EventBus {
+	return &EventBus{client: c}
+}
+
+// Subscribe devuelve un canal con los eventos que cumplen filter. El canal se
+// cierra cuando ctx se cancela o, si filter.Until está fijado, cuando el
+// stream termina una vez pasado ese instante. Ante una desconexión transitoria del daemon,
+// Subscribe reintenta automáticamente con backoff exponencial, ajustando
+// filter.Since para no perder eventos ocurridos durante la reconexión.
+func (b *EventBus) Subscribe(ctx context.Context, filter events.ListOptions) <-chan events.Message {
+	out := make(chan events.Message)
+	go b.run(ctx, filter, out)
+	return out
+}
+
+func (b *EventBus) run(ctx context.Context, filter events.ListOptions, out chan<- events.Message) {
+	defer close(out)
+
+	backoff := initialBackoff
+	for {
+		if b.streamOnce(ctx, &filter, out) {
+			return
+		}
+		// Con Until fijado el daemon cierra el stream al llegar a ese instante:
+		// no es una desconexión, así que no se reconecta.
+		if filter.Until != "" {
+			if until, err := time.Parse(time.RFC3339Nano, filter.Until); err == nil && !time.Now().Before(until) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// streamOnce consume un único stream de eventos hasta que se corte o ctx se
+// cancele. Devuelve true si el llamador debe detenerse por completo.
+func (b *EventBus) streamOnce(ctx context.Context, filter *events.ListOptions, out chan<- events.Message) bool {
+	msgCh, errCh := b.client.Events(ctx, *filter)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case msg, ok := <-msgCh:
+			if !ok {
+				return false
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return true
+			}
+			filter.Since = time.Unix(0, msg.TimeNano).Format(time.RFC3339Nano)
+		case <-errCh:
+			return false
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package imageref parsea referencias de imágenes Docker ("[registry/]repo[:tag]")
+// en sus partes constituyentes, compartido por el update checker y los
+// comandos de la CLI que hablan con el registry para no divergir en casos
+// límite como registries con puerto explícito.
+package imageref
+
+import (
+	"errors"
+	"strings"
+)
+
+var errEmptyName = errors.New("empty image name")
+
+// Ref identifica una imagen por registry, repositorio y tag. Registry queda
+// vacío para imágenes sin calificar (p.ej. "nginx" o "nginx:1.21"), para que
+// el caller pueda resolverlo contra Docker Hub.
+type Ref struct {
+	Registry string
+	Repo     string
+	Tag      string
+}
+
+// Parse separa name en registry, repo y tag. El tag se busca a partir del
+// último ":" del string, no del primero, para manejar correctamente
+// registries con puerto explícito (p.ej. "localhost:5000/nginx:latest").
+func Parse(name string) (Ref, error) {
+	if name == "" {
+		return Ref{}, errEmptyName
+	}
+
+	nameNoTag, tag := name, "latest"
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		nameNoTag, tag = name[:idx], name[idx+1:]
+	}
+
+	registry, repo := "", nameNoTag
+	if idx := strings.Index(nameNoTag, "/"); idx >= 0 {
+		first := nameNoTag[:idx]
+		if looksLikeRegistryHost(first) {
+			registry, repo = first, nameNoTag[idx+1:]
+		}
+	}
+
+	return Ref{Registry: registry, Repo: repo, Tag: tag}, nil
+}
+
+// looksLikeRegistryHost distingue un host de registry ("registry.example.com",
+// "localhost:5000") del primer segmento de ruta de un repo de Docker Hub
+// ("library", "bitnami"), que nunca contiene un punto, un ":" ni "localhost".
+func looksLikeRegistryHost(segment string) bool {
+	return strings.ContainsAny(segment, ".:") || segment == "localhost"
+}
@@ -0,0 +1,37 @@
+package imageref
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Ref
+	}{
+		{"repo sin tag", "nginx", Ref{Registry: "", Repo: "nginx", Tag: "latest"}},
+		{"repo con tag", "nginx:1.21", Ref{Registry: "", Repo: "nginx", Tag: "1.21"}},
+		{"repo de Docker Hub con namespace", "library/nginx:1.21", Ref{Registry: "", Repo: "library/nginx", Tag: "1.21"}},
+		{"registry con dominio", "registry.example.com/app:v1", Ref{Registry: "registry.example.com", Repo: "app", Tag: "v1"}},
+		{"registry con puerto", "localhost:5000/nginx:latest", Ref{Registry: "localhost:5000", Repo: "nginx", Tag: "latest"}},
+		{"registry con dominio y puerto", "myregistry.example.com:5000/app:v1", Ref{Registry: "myregistry.example.com:5000", Repo: "app", Tag: "v1"}},
+		{"localhost sin puerto", "localhost/app:v1", Ref{Registry: "localhost", Repo: "app", Tag: "v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmptyName(t *testing.T) {
+	if _, err := Parse(""); err == nil {
+		t.Fatal("Parse(\"\") error = nil, want error")
+	}
+}
@@ -0,0 +1,493 @@
+// Package registry implementa un cliente mínimo para el protocolo Docker
+// Registry HTTP API V2, incluyendo el desafío de autenticación Bearer usado
+// por Docker Hub y la mayoría de registries compatibles.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultDockerRegistry = "registry-1.docker.io"
+
+// Client habla Registry V2 contra uno o más registries, cacheando los
+// bearer tokens obtenidos hasta que expiran.
+type Client struct {
+	http       *http.Client
+	MaxRetries int
+	RetryDelay time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+
+	dockerConfigOnce sync.Once
+	dockerConfig     dockerConfig
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// NewClient crea un Client listo para usar. httpClient puede ser nil, en cuyo
+// caso se usa http.DefaultClient.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		http:       httpClient,
+		MaxRetries: 3,
+		RetryDelay: time.Second,
+		tokens:     make(map[string]cachedToken),
+	}
+}
+
+// ResolveEndpoint traduce el nombre de registry tal como aparece en el
+// nombre de la imagen al host HTTP real, p.ej. "docker.io" -> registry-1.docker.io.
+func ResolveEndpoint(registry string) string {
+	switch registry {
+	case "", "docker.io", "index.docker.io":
+		return defaultDockerRegistry
+	default:
+		return registry
+	}
+}
+
+// NormalizeRepo antepone "library/" a las imágenes oficiales sin organización
+// cuando se resuelven contra Docker Hub, p.ej. "nginx" -> "library/nginx".
+func NormalizeRepo(registry, repo string) string {
+	if isDockerHub(registry) && !strings.Contains(repo, "/") {
+		return "library/" + repo
+	}
+	return repo
+}
+
+func isDockerHub(registry string) bool {
+	switch registry {
+	case "", "docker.io", "index.docker.io":
+		return true
+	default:
+		return false
+	}
+}
+
+// ListTags devuelve todos los tags de repo, siguiendo la paginación por
+// cabecera Link hasta agotarla.
+func (c *Client) ListTags(ctx context.Context, registryHost, repo string) ([]string, error) {
+	endpoint := ResolveEndpoint(registryHost)
+	repo = NormalizeRepo(registryHost, repo)
+
+	var tags []string
+	url := fmt.Sprintf("https://%s/v2/%s/tags/list", endpoint, repo)
+
+	for url != "" {
+		resp, err := c.doAuthed(ctx, endpoint, repo, "pull", http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&page)
+		next := nextLink(resp.Header.Get("Link"))
+		resp.Body.Close()
+		if decodeErr != nil {
+			return nil, fmt.Errorf("error decoding tags response: %w", decodeErr)
+		}
+
+		tags = append(tags, page.Tags...)
+		if next == "" {
+			break
+		}
+		// El Link header suele traer una referencia relativa (p.ej.
+		// "/v2/library/ubuntu/tags/list?n=100&last=..."), no una URL absoluta.
+		nextURL, err := resolveNextLink(url, next)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving pagination link: %w", err)
+		}
+		url = nextURL
+	}
+
+	return tags, nil
+}
+
+// PlatformManifest describe una entrada de una manifest list (o de un
+// manifest list sintético para una imagen de plataforma única).
+type PlatformManifest struct {
+	Architecture string
+	OS           string
+	Variant      string
+	Size         int64
+	Digest       string
+}
+
+const manifestAcceptHeader = "application/vnd.docker.distribution.manifest.list.v2+json, " +
+	"application/vnd.docker.distribution.manifest.v2+json, " +
+	"application/vnd.oci.image.index.v1+json, " +
+	"application/vnd.oci.image.manifest.v1+json"
+
+// Manifests obtiene la manifest list de repo:tag. Si el registry sólo expone
+// un manifest de plataforma única, se resuelve la plataforma leyendo el blob
+// de configuración de la imagen y se devuelve como una lista de un elemento.
+func (c *Client) Manifests(ctx context.Context, registryHost, repo, tag string) ([]PlatformManifest, error) {
+	endpoint := ResolveEndpoint(registryHost)
+	repo = NormalizeRepo(registryHost, repo)
+
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", endpoint, repo, tag)
+	headers := http.Header{"Accept": []string{manifestAcceptHeader}}
+
+	resp, err := c.doAuthed(ctx, endpoint, repo, "pull", http.MethodGet, url, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		MediaType string `json:"mediaType"`
+		Manifests []struct {
+			MediaType string `json:"mediaType"`
+			Size      int64  `json:"size"`
+			Digest    string `json:"digest"`
+			Platform  struct {
+				Architecture string `json:"architecture"`
+				OS           string `json:"os"`
+				Variant      string `json:"variant"`
+			} `json:"platform"`
+		} `json:"manifests"`
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("error decoding manifest response: %w", err)
+	}
+
+	if len(list.Manifests) > 0 {
+		platforms := make([]PlatformManifest, 0, len(list.Manifests))
+		for _, m := range list.Manifests {
+			platforms = append(platforms, PlatformManifest{
+				Architecture: m.Platform.Architecture,
+				OS:           m.Platform.OS,
+				Variant:      m.Platform.Variant,
+				Size:         m.Size,
+				Digest:       m.Digest,
+			})
+		}
+		return platforms, nil
+	}
+
+	// Manifest de una sola plataforma: resolver arquitectura/OS leyendo el
+	// blob de configuración referenciado.
+	single := PlatformManifest{
+		Digest: resp.Header.Get("Docker-Content-Digest"),
+	}
+	if list.Config.Digest != "" {
+		if cfg, err := c.imageConfig(ctx, endpoint, repo, list.Config.Digest); err == nil {
+			single.Architecture = cfg.Architecture
+			single.OS = cfg.OS
+			single.Variant = cfg.Variant
+		}
+	}
+	return []PlatformManifest{single}, nil
+}
+
+func (c *Client) imageConfig(ctx context.Context, endpoint, repo, digest string) (struct {
+	Architecture string
+	OS           string
+	Variant      string
+}, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", endpoint, repo, digest)
+	resp, err := c.doAuthed(ctx, endpoint, repo, "pull", http.MethodGet, url, nil)
+	var out struct {
+		Architecture string
+		OS           string
+		Variant      string
+	}
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+
+	var cfg struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		Variant      string `json:"variant"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return out, err
+	}
+	out.Architecture, out.OS, out.Variant = cfg.Architecture, cfg.OS, cfg.Variant
+	return out, nil
+}
+
+// ManifestDigest obtiene el Docker-Content-Digest de tag vía HEAD, aceptando
+// tanto manifest lists como manifests individuales (Docker y OCI).
+func (c *Client) ManifestDigest(ctx context.Context, registryHost, repo, tag string) (string, error) {
+	endpoint := ResolveEndpoint(registryHost)
+	repo = NormalizeRepo(registryHost, repo)
+
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", endpoint, repo, tag)
+	accept := strings.Join([]string{
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.oci.image.manifest.v1+json",
+	}, ", ")
+
+	headers := http.Header{"Accept": []string{accept}}
+	resp, err := c.doAuthed(ctx, endpoint, repo, "pull", http.MethodHead, url, headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry response missing Docker-Content-Digest for %s:%s", repo, tag)
+	}
+	return digest, nil
+}
+
+// doAuthed ejecuta method/url con reintentos y, ante un 401, resuelve el
+// desafío Bearer del header WWW-Authenticate y reintenta con el token.
+func (c *Client) doAuthed(ctx context.Context, endpoint, repo, action, method, url string, headers http.Header) (*http.Response, error) {
+	scope := fmt.Sprintf("repository:%s:%s", repo, action)
+
+	var lastErr error
+	delay := c.RetryDelay
+	for attempt := 0; attempt < c.MaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, vs := range headers {
+			for _, v := range vs {
+				req.Header.Add(k, v)
+			}
+		}
+
+		if token := c.cachedToken(endpoint, scope); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			time.Sleep(delay)
+			delay *= 2
+			continue
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+
+			token, ttl, err := c.fetchToken(ctx, endpoint, scope, challenge)
+			if err != nil {
+				return nil, fmt.Errorf("error authenticating against %s: %w", endpoint, err)
+			}
+			c.cacheToken(endpoint, scope, token, ttl)
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		lastErr = fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+}
+
+func (c *Client) cachedToken(endpoint, scope string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.tokens[endpoint+"|"+scope]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return ""
+	}
+	return cached.token
+}
+
+func (c *Client) cacheToken(endpoint, scope, token string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[endpoint+"|"+scope] = cachedToken{token: token, expiresAt: time.Now().Add(ttl)}
+}
+
+var challengeParamRe = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// defaultTokenTTL se usa cuando la respuesta del token omite expires_in,
+// siguiendo el propio fallback de la spec de autenticación de Registry.
+const defaultTokenTTL = 60 * time.Second
+
+// fetchToken resuelve un desafío `Bearer realm="...",service="...",scope="..."`
+// contra realm, añadiendo autenticación básica desde ~/.docker/config.json si
+// hay credenciales guardadas para el registry. Devuelve también el tiempo de
+// vida del token, derivado de expires_in, para que el llamador lo cachee
+// sólo hasta que expire de verdad.
+func (c *Client) fetchToken(ctx context.Context, endpoint, fallbackScope, challenge string) (string, time.Duration, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", 0, fmt.Errorf("unsupported auth challenge: %s", challenge)
+	}
+
+	params := map[string]string{}
+	for _, m := range challengeParamRe.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", 0, fmt.Errorf("auth challenge missing realm: %s", challenge)
+	}
+	service := params["service"]
+	scope := params["scope"]
+	if scope == "" {
+		scope = fallbackScope
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	q := req.URL.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	if user, pass, ok := c.basicAuthFor(endpoint); ok {
+		req.SetBasicAuth(user, pass)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint %s returned status %d", realm, resp.StatusCode)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("error decoding token response: %w", err)
+	}
+
+	ttl := defaultTokenTTL
+	if result.ExpiresIn > 0 {
+		ttl = time.Duration(result.ExpiresIn) * time.Second
+	}
+
+	token := result.Token
+	if token == "" {
+		token = result.AccessToken
+	}
+	return token, ttl, nil
+}
+
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+}
+
+// basicAuthFor busca credenciales para endpoint en ~/.docker/config.json,
+// probando las formas habituales bajo las que Docker guarda la clave.
+func (c *Client) basicAuthFor(endpoint string) (user, pass string, ok bool) {
+	c.dockerConfigOnce.Do(func() {
+		c.dockerConfig = loadDockerConfig()
+	})
+
+	candidates := []string{endpoint, "https://" + endpoint, "https://" + endpoint + "/v1/"}
+	if endpoint == defaultDockerRegistry {
+		candidates = append(candidates, "https://index.docker.io/v1/", "index.docker.io")
+	}
+
+	for _, key := range candidates {
+		entry, found := c.dockerConfig.Auths[key]
+		if !found || entry.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			continue
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		return parts[0], parts[1], true
+	}
+	return "", "", false
+}
+
+func loadDockerConfig() dockerConfig {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return dockerConfig{}
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".docker", "config.json"))
+	if err != nil {
+		return dockerConfig{}
+	}
+
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return dockerConfig{}
+	}
+	return cfg
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+func nextLink(header string) string {
+	if header == "" {
+		return ""
+	}
+	m := linkNextRe.FindStringSubmatch(header)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// resolveNextLink resuelve el target de un Link header contra la URL de la
+// petición que lo devolvió: los registries (Docker Hub, GCR, Quay, Harbor)
+// suelen mandar una referencia relativa, no una URL absoluta.
+func resolveNextLink(requestURL, next string) (string, error) {
+	base, err := neturl.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := neturl.Parse(next)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"vacío", "", ""},
+		{"sin rel=next", `</v2/_catalog?n=100>; rel="first"`, ""},
+		{"relativo", `</v2/library/ubuntu/tags/list?n=100&last=5.0>; rel="next"`, "/v2/library/ubuntu/tags/list?n=100&last=5.0"},
+		{"absoluto", `<https://registry.example.com/v2/app/tags/list?last=1>; rel="next"`, "https://registry.example.com/v2/app/tags/list?last=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLink(tt.header); got != tt.want {
+				t.Errorf("nextLink(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNextLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestURL string
+		next       string
+		want       string
+	}{
+		{
+			name:       "link relativo se resuelve contra el host de la petición",
+			requestURL: "https://registry-1.docker.io/v2/library/ubuntu/tags/list",
+			next:       "/v2/library/ubuntu/tags/list?n=100&last=5.0",
+			want:       "https://registry-1.docker.io/v2/library/ubuntu/tags/list?n=100&last=5.0",
+		},
+		{
+			name:       "link absoluto se respeta tal cual",
+			requestURL: "https://registry-1.docker.io/v2/library/ubuntu/tags/list",
+			next:       "https://other-registry.example.com/v2/app/tags/list?last=1",
+			want:       "https://other-registry.example.com/v2/app/tags/list?last=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveNextLink(tt.requestURL, tt.next)
+			if err != nil {
+				t.Fatalf("resolveNextLink() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveNextLink(%q, %q) = %q, want %q", tt.requestURL, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientTokenCache(t *testing.T) {
+	c := NewClient(nil)
+
+	if got := c.cachedToken("registry-1.docker.io", "repository:library/ubuntu:pull"); got != "" {
+		t.Fatalf("cachedToken() on empty cache = %q, want empty", got)
+	}
+
+	c.cacheToken("registry-1.docker.io", "repository:library/ubuntu:pull", "tok-1", time.Hour)
+	if got := c.cachedToken("registry-1.docker.io", "repository:library/ubuntu:pull"); got != "tok-1" {
+		t.Fatalf("cachedToken() = %q, want %q", got, "tok-1")
+	}
+
+	c.cacheToken("registry-1.docker.io", "repository:library/ubuntu:pull", "tok-2", -time.Second)
+	if got := c.cachedToken("registry-1.docker.io", "repository:library/ubuntu:pull"); got != "" {
+		t.Fatalf("cachedToken() after expiry = %q, want empty", got)
+	}
+}
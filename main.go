@@ -2,10 +2,9 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os/exec"
 	"strings"
 	"sync"
@@ -13,24 +12,23 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/spf13/viper"
-)
 
-type ImageInfo struct {
-	Registry string
-	Repo     string
-	Tag      string
-}
+	"github.com/elliotsecops/DockOps/internal/imageref"
+	"github.com/elliotsecops/DockOps/internal/registry"
+)
 
 type Config struct {
-	GCRAccessToken string        `mapstructure:"gcr_access_token"`
-	RateLimit      time.Duration `mapstructure:"rate_limit"`
-	MaxRetries     int           `mapstructure:"max_retries"`
-	RetryDelay     time.Duration `mapstructure:"retry_delay"`
+	RateLimit  time.Duration `mapstructure:"rate_limit"`
+	MaxRetries int           `mapstructure:"max_retries"`
+	RetryDelay time.Duration `mapstructure:"retry_delay"`
 }
 
 var config Config
 
 func main() {
+	includePrerelease := flag.Bool("include-prerelease", false, "Consider pre-release tags (e.g. 1.2.0-rc1) when looking for updates")
+	flag.Parse()
+
 	if err := loadConfig(); err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
@@ -38,8 +36,9 @@ func main() {
 	rateLimiter := time.NewTicker(config.RateLimit)
 	defer rateLimiter.Stop()
 
-	client := &http.Client{}
-	cache := make(map[string][]string)
+	regClient := registry.NewClient(nil)
+	regClient.MaxRetries = config.MaxRetries
+	regClient.RetryDelay = config.RetryDelay
 
 	localImages, err := getLocalImages()
 	if err != nil {
@@ -51,10 +50,10 @@ func main() {
 
 	for _, image := range localImages {
 		wg.Add(1)
-		go func(img ImageInfo) {
+		go func(img imageref.Ref) {
 			defer wg.Done()
 			<-rateLimiter.C
-			if update, err := checkForUpdates(client, img, cache); err != nil {
+			if update, err := checkForUpdates(regClient, img, *includePrerelease); err != nil {
 				log.Printf("Error checking for updates for %s: %v", img.Repo, err)
 			} else if update != "" {
 				results <- update
@@ -103,132 +102,102 @@ func loadConfig() error {
 	return nil
 }
 
-func checkForUpdates(client *http.Client, image ImageInfo, cache map[string][]string) (string, error) {
-	remoteTags, err := getRemoteTags(client, image.Registry, image.Repo, "gcloud", cache)
+func checkForUpdates(regClient *registry.Client, image imageref.Ref, includePrerelease bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	remoteTags, err := regClient.ListTags(ctx, image.Registry, image.Repo)
 	if err != nil {
 		return "", fmt.Errorf("error getting remote tags: %w", err)
 	}
 
-	currentVer, err := semver.NewVersion(image.Tag)
-	if err != nil {
-		return "", fmt.Errorf("error parsing current version: %w", err)
-	}
+	currentVer, verErr := semver.NewVersion(image.Tag)
 
 	var latestVer *semver.Version
 	for _, tag := range remoteTags {
-		if v, err := semver.NewVersion(tag); err == nil {
-			if latestVer == nil || v.GreaterThan(latestVer) {
-				latestVer = v
-			}
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if !includePrerelease && v.Prerelease() != "" {
+			continue
+		}
+		if latestVer == nil || v.GreaterThan(latestVer) {
+			latestVer = v
 		}
 	}
 
-	if latestVer != nil && latestVer.GreaterThan(currentVer) {
-		return fmt.Sprintf("Update available for %s: %s -> %s", image.Repo, currentVer, latestVer), nil
+	if verErr == nil && latestVer != nil {
+		if latestVer.GreaterThan(currentVer) {
+			return fmt.Sprintf("Update available for %s: %s -> %s", image.Repo, currentVer, latestVer), nil
+		}
+		return "", nil
 	}
 
-	return "", nil
+	// The current tag (e.g. "latest") isn't semver-parseable, or the registry
+	// doesn't publish semver tags: fall back to comparing digests.
+	return checkForUpdatesByDigest(ctx, regClient, image)
 }
 
-func getLocalImages() ([]ImageInfo, error) {
-	cmd := exec.Command("docker", "image", "ls", "--format", "{{.Repository}}:{{.Tag}}")
-	output, err := cmd.Output()
+func checkForUpdatesByDigest(ctx context.Context, regClient *registry.Client, image imageref.Ref) (string, error) {
+	localDigest, err := localImageDigest(image)
 	if err != nil {
-		return nil, fmt.Errorf("error executing docker command: %w", err)
+		return "", fmt.Errorf("error reading local image digest: %w", err)
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var images []ImageInfo
-
-	for _, line := range lines {
-		img, err := parseImageName(line)
-		if err != nil {
-			log.Printf("Error parsing image name %s: %v", line, err)
-			continue
-		}
-		images = append(images, img)
+	if localDigest == "" {
+		return "", nil
 	}
 
-	return images, nil
-}
-
-func getRemoteTags(client *http.Client, registry, repo, authMethod string, cache map[string][]string) ([]string, error) {
-	cacheKey := registry + "/" + repo
-	if cachedTags, ok := cache[cacheKey]; ok {
-		return cachedTags, nil
+	remoteDigest, err := regClient.ManifestDigest(ctx, image.Registry, image.Repo, image.Tag)
+	if err != nil {
+		return "", fmt.Errorf("error fetching remote manifest digest: %w", err)
 	}
 
-	var resp *http.Response
-	var err error
-	retryDelay := config.RetryDelay
-
-	for i := 0; i < config.MaxRetries; i++ {
-		url := fmt.Sprintf("%s/repositories/%s/tags/?page_size=1000", registry, repo)
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		var req *http.Request
-		req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("error creating HTTP request: %w", err)
-		}
-
-		if authMethod == "gcloud" {
-			if config.GCRAccessToken == "" {
-				return nil, fmt.Errorf("GCR_ACCESS_TOKEN not set in config")
-			}
-			req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(config.GCRAccessToken))
-		}
-
-		resp, err = client.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			break
-		}
-		if i < config.MaxRetries-1 {
-			log.Printf("Retry %d: Error fetching tags, retrying in %s: %v", i+1, retryDelay, err)
-			time.Sleep(retryDelay)
-			retryDelay *= 2
-			continue
-		}
-		if err != nil {
-			return nil, fmt.Errorf("max retries exceeded: error fetching tags: %w", err)
-		}
-		return nil, fmt.Errorf("max retries exceeded: unexpected status code: %d", resp.StatusCode)
+	if remoteDigest != localDigest {
+		return fmt.Sprintf("Update available for %s:%s (new digest %s)", image.Repo, image.Tag, remoteDigest), nil
 	}
+	return "", nil
+}
 
-	defer resp.Body.Close()
-
-	var result struct {
-		Tags []string `json:"tags"`
+func localImageDigest(image imageref.Ref) (string, error) {
+	ref := fmt.Sprintf("%s/%s:%s", image.Registry, image.Repo, image.Tag)
+	if image.Registry == "" {
+		ref = fmt.Sprintf("%s:%s", image.Repo, image.Tag)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding JSON response: %w", err)
+	cmd := exec.Command("docker", "image", "inspect", ref, "--format", "{{index .RepoDigests 0}}")
+	output, err := cmd.Output()
+	if err != nil {
+		// No RepoDigests (image never pushed/pulled with a digest): nothing to compare.
+		return "", nil
 	}
 
-	cache[cacheKey] = result.Tags
-	return result.Tags, nil
+	repoDigest := strings.TrimSpace(string(output))
+	parts := strings.SplitN(repoDigest, "@", 2)
+	if len(parts) != 2 {
+		return "", nil
+	}
+	return parts[1], nil
 }
 
-func parseImageName(name string) (ImageInfo, error) {
-	parts := strings.Split(name, "/")
-	if len(parts) < 2 {
-		return ImageInfo{}, fmt.Errorf("invalid image name format")
+func getLocalImages() ([]imageref.Ref, error) {
+	cmd := exec.Command("docker", "image", "ls", "--format", "{{.Repository}}:{{.Tag}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error executing docker command: %w", err)
 	}
 
-	registry := parts[0]
-	repo := strings.Join(parts[1:len(parts)-1], "/")
-	tagParts := strings.Split(parts[len(parts)-1], ":")
-	if len(tagParts) != 2 {
-		return ImageInfo{}, fmt.Errorf("invalid tag format")
-	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var images []imageref.Ref
 
-	repo += "/" + tagParts[0]
-	tag := tagParts[1]
+	for _, line := range lines {
+		img, err := imageref.Parse(line)
+		if err != nil {
+			log.Printf("Error parsing image name %s: %v", line, err)
+			continue
+		}
+		images = append(images, img)
+	}
 
-	return ImageInfo{
-		Registry: registry,
-		Repo:     repo,
-		Tag:      tag,
-	}, nil
+	return images, nil
 }